@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Default directory for the on-disk cache tier, relative to the working directory
+	DefaultDiskCacheDir = "cache_data"
+	// Default disk cache budget (500 MiB)
+	DefaultDiskCacheMaxBytes = 500 * 1024 * 1024
+)
+
+// DiskCache is a second cache tier behind the in-memory LRU: it persists
+// encoded image bytes to disk so the cache survives a restart, evicting the
+// least-recently-used files once the directory exceeds maxBytes.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// NewDiskCache creates a disk cache rooted at dir, creating it if needed.
+// A non-positive maxBytes disables the cache (NewDiskCache returns nil, nil).
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if maxBytes <= 0 {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir, maxBytes: maxBytes}, nil
+}
+
+// path returns the on-disk path for a cache key. It must not be called on a
+// nil receiver; Get and Set check for a disabled (nil) cache first.
+func (dc *DiskCache) path(key string) string {
+	return filepath.Join(dc.dir, key+".bin")
+}
+
+// Get reads cached bytes for key, reporting whether they were found. A nil
+// DiskCache always reports a miss.
+func (dc *DiskCache) Get(key string) ([]byte, bool) {
+	if dc == nil {
+		return nil, false
+	}
+
+	p := dc.path(key)
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+
+	now := time.Now()
+	_ = os.Chtimes(p, now, now) // bump mtime so LRU eviction treats this as recently used
+
+	return data, true
+}
+
+// Set writes data for key to disk and evicts the least-recently-used files
+// if the directory has grown past maxBytes. A nil DiskCache is a no-op.
+func (dc *DiskCache) Set(key string, data []byte) {
+	if dc == nil {
+		return
+	}
+	if err := os.WriteFile(dc.path(key), data, 0o644); err != nil {
+		return
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.evict()
+}
+
+// evict removes the least-recently-modified files until the directory's
+// total size is back under maxBytes. Caller must hold dc.mu.
+func (dc *DiskCache) evict() {
+	entries, err := os.ReadDir(dc.dir)
+	if err != nil {
+		return
+	}
+
+	type fileInfo struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var files []fileInfo
+	var total int64
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, fileInfo{path: filepath.Join(dc.dir, e.Name()), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+	if total <= dc.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= dc.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewDiskCacheDisabledForNonPositiveMaxBytes(t *testing.T) {
+	dc, err := NewDiskCache(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+	if dc != nil {
+		t.Fatalf("got non-nil DiskCache for maxBytes=0, want nil (disabled)")
+	}
+
+	// A nil *DiskCache must be safe to call through.
+	if _, found := dc.Get("key"); found {
+		t.Errorf("nil DiskCache reported a hit")
+	}
+	dc.Set("key", []byte("data")) // must not panic
+}
+
+func TestDiskCacheGetSetRoundTrip(t *testing.T) {
+	dc, err := NewDiskCache(t.TempDir(), 1024*1024)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	dc.Set("key", []byte("payload"))
+	data, found := dc.Get("key")
+	if !found {
+		t.Fatalf("Get(key) missed after Set")
+	}
+	if string(data) != "payload" {
+		t.Errorf("Get(key) = %q, want %q", data, "payload")
+	}
+
+	if _, found := dc.Get("missing"); found {
+		t.Errorf("Get(missing) reported a hit")
+	}
+}
+
+func TestDiskCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	dc, err := NewDiskCache(dir, 20) // small budget: only a couple of 10-byte entries fit
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	payload := []byte("0123456789") // 10 bytes
+	dc.Set("oldest", payload)
+
+	// Force distinct mtimes so LRU ordering is unambiguous regardless of
+	// filesystem timestamp resolution.
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "oldest.bin"), old, old); err != nil {
+		t.Fatalf("Chtimes: %v", err)
+	}
+
+	dc.Set("newer", payload)
+	dc.Set("newest", payload) // total would be 30 bytes, over the 20-byte budget
+
+	if _, found := dc.Get("oldest"); found {
+		t.Errorf("least-recently-used entry was not evicted")
+	}
+	if _, found := dc.Get("newest"); !found {
+		t.Errorf("most-recently-written entry was evicted")
+	}
+}
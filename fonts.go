@@ -0,0 +1,289 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+
+	"github.com/gopkg-dev/placeholder/cache"
+)
+
+//go:embed fonts/DouyinSansBold.otf
+var embeddedLatinFont []byte
+
+//go:embed fonts/NotoSansCJKsc-Regular.otf
+var embeddedCJKFont []byte
+
+const (
+	// Maximum number of minted faces to keep cached across all typefaces/sizes
+	FONT_FACE_CACHE_SIZE = 256
+	// Default DPI used when minting faces for raster output
+	DefaultDPI = 72.0
+)
+
+// Typeface identifies a registered font family, independent of size/weight/style.
+type Typeface string
+
+const (
+	TypefaceLatin Typeface = "latin" // Basic Latin / Latin-1 text
+	TypefaceCJK   Typeface = "cjk"   // Chinese/Japanese/Korean text
+)
+
+// Style selects an italic/oblique variant of a typeface.
+type Style string
+
+const (
+	StyleNormal Style = "normal"
+	StyleItalic Style = "italic"
+)
+
+// Weight selects a font weight variant of a typeface.
+type Weight string
+
+const (
+	WeightRegular Weight = "regular"
+	WeightBold    Weight = "bold"
+)
+
+// fontKey identifies one registered (typeface, style, weight) combination.
+type fontKey struct {
+	Typeface Typeface
+	Style    Style
+	Weight   Weight
+}
+
+// String returns a stable identifier for the key, suitable for use as a
+// cache key by backends (e.g. render.Draw2DRenderer) that need to recognize
+// "same font as last time" without rehashing the raw bytes on every call.
+func (k fontKey) String() string {
+	return fmt.Sprintf("%s-%s-%s", k.Typeface, k.Style, k.Weight)
+}
+
+// faceKey identifies one minted face, which is additionally size/DPI specific.
+type faceKey struct {
+	fontKey
+	Size float64
+	DPI  float64
+}
+
+// FontRegistry holds one parsed *opentype.Font per (typeface, style, weight),
+// alongside the raw bytes it was parsed from (needed by backends like
+// render.Draw2DRenderer that parse fonts through their own stack rather than
+// font.Face), and mints size-specific faces on demand, caching them by
+// (font, size, dpi) in a bounded LRU so repeated requests don't reparse or re-mint.
+type FontRegistry struct {
+	mu    sync.RWMutex
+	fonts map[fontKey]*opentype.Font
+	raw   map[fontKey][]byte
+	faces *cache.LruCache // faceKey -> font.Face
+}
+
+// NewFontRegistry builds a registry pre-loaded with the embedded Latin and CJK typefaces.
+func NewFontRegistry() *FontRegistry {
+	reg := &FontRegistry{
+		fonts: make(map[fontKey]*opentype.Font),
+		raw:   make(map[fontKey][]byte),
+		faces: cache.New(cache.WithSize(FONT_FACE_CACHE_SIZE)),
+	}
+
+	if f, err := opentype.Parse(embeddedLatinFont); err == nil {
+		key := fontKey{TypefaceLatin, StyleNormal, WeightBold}
+		reg.fonts[key] = f
+		reg.raw[key] = embeddedLatinFont
+	}
+	if f, err := opentype.Parse(embeddedCJKFont); err == nil {
+		key := fontKey{TypefaceCJK, StyleNormal, WeightRegular}
+		reg.fonts[key] = f
+		reg.raw[key] = embeddedCJKFont
+	}
+
+	if dir := os.Getenv("PLACEHOLDER_FONT_DIR"); dir != "" {
+		if err := reg.LoadDir(dir); err != nil {
+			log.Printf("font registry: %v", err)
+		}
+	}
+
+	return reg
+}
+
+// LoadDir scans dir non-recursively for "*.otf"/"*.ttf" files named
+// "typeface-style-weight.ext" (e.g. "latin-italic-bold.ttf") and registers
+// each one, letting deployments add typefaces beyond the two embedded
+// defaults without a rebuild. Files that don't match the naming convention
+// or fail to read or parse are logged and skipped rather than aborting the
+// scan; only a failure to read dir itself is returned as an error.
+func (r *FontRegistry) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read font dir %s: %w", dir, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if ext != ".otf" && ext != ".ttf" {
+			continue
+		}
+
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		parts := strings.Split(name, "-")
+		if len(parts) != 3 {
+			log.Printf("font registry: skipping %s: expected typeface-style-weight filename", entry.Name())
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			log.Printf("font registry: reading %s: %v", entry.Name(), err)
+			continue
+		}
+
+		if err := r.Register(Typeface(parts[0]), Style(parts[1]), Weight(parts[2]), data); err != nil {
+			log.Printf("font registry: %v", err)
+		}
+	}
+	return nil
+}
+
+// Register parses raw font bytes once and adds them, along with the bytes
+// themselves, to the registry under the given key.
+func (r *FontRegistry) Register(typeface Typeface, style Style, weight Weight, data []byte) error {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("parse font %s/%s/%s: %w", typeface, style, weight, err)
+	}
+
+	key := fontKey{typeface, style, weight}
+	r.mu.Lock()
+	r.fonts[key] = f
+	r.raw[key] = data
+	r.mu.Unlock()
+	return nil
+}
+
+// Font returns the parsed *opentype.Font for typeface/style/weight, falling
+// back to the default Latin/bold font when the requested combination isn't
+// registered. Useful for glyph-metric work that needs the font itself rather
+// than a size-specific face.
+func (r *FontRegistry) Font(typeface Typeface, style Style, weight Weight) (*opentype.Font, fontKey, error) {
+	key, err := r.resolve(typeface, style, weight)
+	if err != nil {
+		return nil, key, err
+	}
+
+	r.mu.RLock()
+	parsed := r.fonts[key]
+	r.mu.RUnlock()
+	return parsed, key, nil
+}
+
+// Raw returns the raw font bytes for typeface/style/weight, falling back to
+// the default Latin/bold font like Font does. Backends that parse fonts
+// through their own stack (e.g. render.Draw2DRenderer's truetype-based text
+// pipeline) use this instead of a font.Face.
+func (r *FontRegistry) Raw(typeface Typeface, style Style, weight Weight) ([]byte, fontKey, error) {
+	key, err := r.resolve(typeface, style, weight)
+	if err != nil {
+		return nil, key, err
+	}
+
+	r.mu.RLock()
+	data := r.raw[key]
+	r.mu.RUnlock()
+	return data, key, nil
+}
+
+// resolve looks up the registered fontKey for typeface/style/weight, falling
+// back to the default Latin/bold key when the requested combination isn't registered.
+func (r *FontRegistry) resolve(typeface Typeface, style Style, weight Weight) (fontKey, error) {
+	key := fontKey{typeface, style, weight}
+
+	r.mu.RLock()
+	_, ok := r.fonts[key]
+	r.mu.RUnlock()
+
+	if !ok {
+		key = fontKey{TypefaceLatin, StyleNormal, WeightBold}
+		r.mu.RLock()
+		_, ok = r.fonts[key]
+		r.mu.RUnlock()
+		if !ok {
+			return key, fmt.Errorf("no fonts registered")
+		}
+	}
+
+	return key, nil
+}
+
+// Face returns a face for the requested typeface/style/weight/size, minting
+// and caching it on first use. It falls back to the default Latin/bold face
+// when the requested combination isn't registered.
+func (r *FontRegistry) Face(typeface Typeface, style Style, weight Weight, size, dpi float64) (font.Face, error) {
+	parsed, key, err := r.Font(typeface, style, weight)
+	if err != nil {
+		return nil, err
+	}
+
+	fk := faceKey{key, size, dpi}
+	if cached, found := r.faces.Get(fk); found {
+		if face, ok := cached.(font.Face); ok {
+			return face, nil
+		}
+	}
+
+	face, err := opentype.NewFace(parsed, &opentype.FaceOptions{Size: size, DPI: dpi})
+	if err != nil {
+		return nil, err
+	}
+	r.faces.Set(fk, face)
+	return face, nil
+}
+
+// DetectTypeface returns TypefaceCJK when text contains any code point beyond
+// U+2E7F (i.e. from the CJK Radicals Supplement block onward), so Chinese,
+// Japanese and Korean text renders with a CJK-capable face instead of tofu.
+func DetectTypeface(text string) Typeface {
+	for _, r := range text {
+		if r > 0x2E7F {
+			return TypefaceCJK
+		}
+	}
+	return TypefaceLatin
+}
+
+// ParseTypeface maps a ?font= query value to a Typeface, defaulting to Latin.
+func ParseTypeface(s string) Typeface {
+	switch s {
+	case "", "latin":
+		return TypefaceLatin
+	case "cjk":
+		return TypefaceCJK
+	default:
+		return Typeface(s)
+	}
+}
+
+// ParseStyle maps a ?style= query value to a Style, defaulting to normal.
+func ParseStyle(s string) Style {
+	if s == "italic" {
+		return StyleItalic
+	}
+	return StyleNormal
+}
+
+// ParseWeight maps a ?weight= query value to a Weight, defaulting to regular.
+func ParseWeight(s string) Weight {
+	if s == "bold" {
+		return WeightBold
+	}
+	return WeightRegular
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestDetectTypeface(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want Typeface
+	}{
+		{"empty", "", TypefaceLatin},
+		{"ascii", "Hello World", TypefaceLatin},
+		{"latin-1 supplement", "café", TypefaceLatin},
+		{"chinese", "你好", TypefaceCJK},
+		{"japanese", "こんにちは", TypefaceCJK},
+		{"korean", "안녕하세요", TypefaceCJK},
+		{"mixed ascii and cjk", "Hello 世界", TypefaceCJK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectTypeface(tt.text); got != tt.want {
+				t.Errorf("DetectTypeface(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTypeface(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Typeface
+	}{
+		{"", TypefaceLatin},
+		{"latin", TypefaceLatin},
+		{"cjk", TypefaceCJK},
+		{"monospace", Typeface("monospace")},
+	}
+	for _, tt := range tests {
+		if got := ParseTypeface(tt.in); got != tt.want {
+			t.Errorf("ParseTypeface(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseStyleAndWeight(t *testing.T) {
+	if got := ParseStyle("italic"); got != StyleItalic {
+		t.Errorf("ParseStyle(italic) = %q, want %q", got, StyleItalic)
+	}
+	if got := ParseStyle("bogus"); got != StyleNormal {
+		t.Errorf("ParseStyle(bogus) = %q, want %q", got, StyleNormal)
+	}
+	if got := ParseWeight("bold"); got != WeightBold {
+		t.Errorf("ParseWeight(bold) = %q, want %q", got, WeightBold)
+	}
+	if got := ParseWeight("bogus"); got != WeightRegular {
+		t.Errorf("ParseWeight(bogus) = %q, want %q", got, WeightRegular)
+	}
+}
@@ -3,86 +3,145 @@ package main
 import (
 	"bytes"
 	"crypto/md5"
-	_ "embed"
 	"fmt"
 	"image"
 	"image/color"
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"log"
+	"os"
 	"strconv"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/chai2010/webp"
-	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+
 	"github.com/gopkg-dev/placeholder/cache"
+	"github.com/gopkg-dev/placeholder/render"
 )
 
-//go:embed fonts/DouyinSansBold.otf
-var embeddedFont []byte
-
 const (
 	// Maximum number of cached images in memory
 	MAX_CACHE_ITEMS = 10000
 	// Cache TTL in seconds (1 hour)
 	CACHE_MAX_AGE = 3600
+
+	// Fraction of width/height a laid-out text block may occupy before we shrink the font
+	targetWidthRatio  = 0.85
+	targetHeightRatio = 0.85
+	// Font size is multiplied by this each time a layout attempt doesn't fit
+	layoutShrinkFactor = 0.92
+	// Upper bound on shrink iterations in createImage
+	maxLayoutAttempts = 6
+	// Floor below which we stop shrinking and draw with whatever we have
+	minLayoutFontSize = 8.0
 )
 
 // ImageGenerator handles placeholder image generation with memory caching
 type ImageGenerator struct {
 	memoryCache *cache.LruCache // LRU cache for generated images
-	fontPool    *FontPool       // Pool of pre-parsed fonts for reuse
+	diskCache   *DiskCache      // Second-tier on-disk cache, nil if disabled
+	fonts       *FontRegistry   // Registry of parsed typefaces and minted faces
 }
 
-// NewImageGenerator creates a new image generator with LRU cache and font pool
+// NewImageGenerator creates a new image generator with LRU cache, an optional
+// on-disk cache tier (configured via PLACEHOLDER_DISK_CACHE_DIR /
+// PLACEHOLDER_DISK_CACHE_MAX_BYTES), and a font registry.
 func NewImageGenerator() *ImageGenerator {
+	diskCache, err := NewDiskCache(diskCacheDirFromEnv(), diskCacheMaxBytesFromEnv())
+	if err != nil {
+		log.Printf("disk cache disabled: %v", err)
+	}
+
 	return &ImageGenerator{
 		memoryCache: cache.New(
 			cache.WithSize(MAX_CACHE_ITEMS),
 			cache.WithAge(CACHE_MAX_AGE),
 			cache.WithUpdateAgeOnGet(),
 		),
-		fontPool: NewFontPool(FONT_POOL_SIZE),
+		diskCache: diskCache,
+		fonts:     NewFontRegistry(),
 	}
 }
 
-// GenerateImage creates or retrieves a cached placeholder image based on request parameters
-func (ig *ImageGenerator) GenerateImage(req *ImageRequest) ([]byte, error) {
+// diskCacheDirFromEnv reads PLACEHOLDER_DISK_CACHE_DIR, defaulting to DefaultDiskCacheDir.
+func diskCacheDirFromEnv() string {
+	if dir := os.Getenv("PLACEHOLDER_DISK_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return DefaultDiskCacheDir
+}
+
+// diskCacheMaxBytesFromEnv reads PLACEHOLDER_DISK_CACHE_MAX_BYTES, defaulting
+// to DefaultDiskCacheMaxBytes. A value of "0" disables the disk cache tier.
+func diskCacheMaxBytesFromEnv() int64 {
+	raw := os.Getenv("PLACEHOLDER_DISK_CACHE_MAX_BYTES")
+	if raw == "" {
+		return DefaultDiskCacheMaxBytes
+	}
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return DefaultDiskCacheMaxBytes
+	}
+	return n
+}
+
+// GenerateImage creates or retrieves a cached placeholder image based on
+// request parameters. Set noCache to bypass both cache tiers (?nocache=1).
+func (ig *ImageGenerator) GenerateImage(req *ImageRequest, noCache bool) ([]byte, error) {
 	cacheKey := ig.getCacheKey(req)
 
-	// Check memory cache
-	if data, found := ig.memoryCache.Get(cacheKey); found {
-		if imageData, ok := data.([]byte); ok {
-			return imageData, nil
+	if !noCache {
+		if data, found := ig.memoryCache.Get(cacheKey); found {
+			if imageData, ok := data.([]byte); ok {
+				return imageData, nil
+			}
+		}
+
+		if data, found := ig.diskCache.Get(cacheKey); found {
+			ig.memoryCache.Set(cacheKey, data)
+			return data, nil
 		}
 	}
 
-	// Generate new image
-	img, err := ig.createImage(req)
+	data, err := ig.render(req)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := ig.encodeImage(img, req.Type)
+	if !noCache {
+		ig.memoryCache.Set(cacheKey, data)
+		ig.diskCache.Set(cacheKey, data)
+	}
+	return data, nil
+}
+
+// render produces the encoded image bytes for req, independent of caching.
+func (ig *ImageGenerator) render(req *ImageRequest) ([]byte, error) {
+	// SVG bypasses the raster pipeline entirely and is emitted directly as XML
+	if req.Type == "svg" {
+		return ig.createSVG(req)
+	}
+
+	img, err := ig.createImage(req)
 	if err != nil {
 		return nil, err
 	}
-
-	// Store in memory cache
-	ig.memoryCache.Set(cacheKey, data)
-	return data, nil
+	return ig.encodeImage(img, req.Type)
 }
 
 // createImage generates a new placeholder image with text and colors
 func (ig *ImageGenerator) createImage(req *ImageRequest) (image.Image, error) {
-	dc := gg.NewContext(req.Width, req.Height)
+	dc := render.New(req.Renderer, req.Width, req.Height)
 
 	bgColor, err := parseHexColor(req.BgColor)
 	if err != nil {
 		return nil, err
 	}
-	dc.SetColor(bgColor)
-	dc.Clear()
+	dc.Fill(bgColor)
 
 	fgColor, err := parseHexColor(req.FgColor)
 	if err != nil {
@@ -90,34 +149,60 @@ func (ig *ImageGenerator) createImage(req *ImageRequest) (image.Image, error) {
 	}
 	dc.SetColor(fgColor)
 
-	fontSize := calculateOptimalFontSize(req.Width, req.Height, req.Text)
+	if !utf8.ValidString(req.Text) {
+		req.Text = "Invalid UTF-8"
+	}
 
-	fontFace := ig.fontPool.GetFont(fontSize)
-	if fontFace != nil {
-		dc.SetFontFace(*fontFace)
-		defer ig.fontPool.PutFont(fontFace)
-	} else {
-		dc.LoadFontFace("", fontSize)
+	typeface := ParseTypeface(req.Font)
+	if req.Font == "" {
+		typeface = DetectTypeface(req.Text)
 	}
 
-	if !utf8.ValidString(req.Text) {
-		req.Text = "Invalid UTF-8"
+	parsedFont, _, err := ig.fonts.Font(typeface, req.Style, req.Weight)
+	if err != nil {
+		return nil, fmt.Errorf("load font: %w", err)
 	}
 
-	// Calculate center position with empirical adjustment for visual centering
-	centerX := float64(req.Width) / 2
+	// Shrink the font size until the laid-out block's widest line and total
+	// inked height both fit inside the target box, or we hit the floor.
+	fontSize := calculateOptimalFontSize(req.Width, req.Height, widestLine(req.Text))
+	maxWidth := fixed.I(int(float64(req.Width) * targetWidthRatio))
+	maxHeight := fixed.I(int(float64(req.Height) * targetHeightRatio))
+
+	var fontFace font.Face
+	var block *textBlock
+	for attempt := 0; attempt < maxLayoutAttempts; attempt++ {
+		fontFace, err = ig.fonts.Face(typeface, req.Style, req.Weight, fontSize, DefaultDPI)
+		if err != nil {
+			return nil, fmt.Errorf("load font face: %w", err)
+		}
 
-	// Use MeasureString to get text dimensions and calculate visual offset
-	_, textHeight := dc.MeasureString(req.Text)
+		block, err = layoutText(parsedFont, fontFace, req.Text, fontSize, DefaultDPI, maxWidth)
+		if err != nil {
+			return nil, fmt.Errorf("layout text: %w", err)
+		}
 
-	// TODO: This 0.15 is an empirical guess based on typical font line spacing
-	// A more precise approach would require accessing font metrics directly
-	// For now, this value can be tuned based on visual testing results
-	visualOffset := textHeight * 0.15
-	centerY := float64(req.Height)/2 - visualOffset
+		if block.fits(maxWidth, maxHeight) || fontSize <= minLayoutFontSize {
+			break
+		}
+		fontSize *= layoutShrinkFactor
+	}
+	rawFont, fontKey, err := ig.fonts.Raw(typeface, req.Style, req.Weight)
+	if err != nil {
+		return nil, fmt.Errorf("load font bytes: %w", err)
+	}
+	dc.SetFont(fontFace, rawFont, fontKey.String(), fontSize, DefaultDPI)
 
-	// Draw text with center anchors
-	dc.DrawStringAnchored(req.Text, centerX, centerY, 0.5, 0.5)
+	// Center the block's ink bounding box on the image, then draw each line
+	// centered horizontally at its own baseline.
+	centerX := float64(req.Width) / 2
+	centerY := float64(req.Height) / 2
+	firstBaselineY := centerY - fixedToFloat(block.InkTop+block.InkBottom)/2
+
+	for i, line := range block.Lines {
+		baselineY := firstBaselineY + float64(i)*fixedToFloat(block.LineHeight)
+		dc.DrawTextAnchored(line.Text, centerX, baselineY, 0.5, 0)
+	}
 
 	return dc.Image(), nil
 }
@@ -146,7 +231,7 @@ func (ig *ImageGenerator) encodeImage(img image.Image, imageType string) ([]byte
 
 // getCacheKey generates MD5 hash for cache key based on image parameters
 func (ig *ImageGenerator) getCacheKey(req *ImageRequest) string {
-	key := fmt.Sprintf("%dx%d_%s_%s_%s_%s", req.Width, req.Height, req.Type, req.BgColor, req.FgColor, req.Text)
+	key := fmt.Sprintf("%dx%d_%s_%s_%s_%s_%s_%s_%s_%s", req.Width, req.Height, req.Type, req.BgColor, req.FgColor, req.Text, req.Font, req.Weight, req.Style, req.Renderer)
 	hash := md5.Sum([]byte(key))
 	return fmt.Sprintf("%x", hash)
 }
@@ -173,7 +258,23 @@ func parseHexColor(hexColor string) (color.RGBA, error) {
 	return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}, nil
 }
 
-// calculateOptimalFontSize computes appropriate font size based on image dimensions and text length
+// widestLine returns the line (split on "\n") with the most runes, so a
+// multi-line request sizes its initial font guess off the line that will
+// actually determine the block's width rather than the total character count.
+func widestLine(text string) string {
+	widest := text
+	widestCount := -1
+	for _, line := range strings.Split(text, "\n") {
+		if n := utf8.RuneCountInString(line); n > widestCount {
+			widest, widestCount = line, n
+		}
+	}
+	return widest
+}
+
+// calculateOptimalFontSize computes a starting font size based on image
+// dimensions and text length. It's an initial guess only — createImage
+// refines it against the real laid-out block in its layoutText shrink loop.
 func calculateOptimalFontSize(width, height int, text string) float64 {
 	minDim := float64(width)
 	if height < width {
@@ -187,7 +288,6 @@ func calculateOptimalFontSize(width, height int, text string) float64 {
 
 	// Enhanced algorithm with much more aggressive scaling for dramatic text sizes
 	// Base font size calculation considering text length and available space
-	targetWidthRatio := 0.85 // Use 85% of width for text (increased from 85%)
 	avgCharWidth := (float64(width) * targetWidthRatio) / runeCount
 
 	// More aggressive scale factor for bigger impact
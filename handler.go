@@ -17,10 +17,15 @@ func init() {
 
 // RequestQueryParams represents HTTP request parameters for image generation
 type RequestQueryParams struct {
-	Size string `params:"size"` // Image size in WxH format
-	Bg   string `query:"bg"`    // Background color (hex)
-	Fg   string `query:"fg"`    // Foreground color (hex)
-	Text string `query:"text"`  // Custom text to display
+	Size     string `params:"size"`     // Image size in WxH format
+	Bg       string `query:"bg"`        // Background color (hex, 3-char hex, rgb()/rgba(), or CSS name)
+	Fg       string `query:"fg"`        // Foreground color (hex, 3-char hex, rgb()/rgba(), or CSS name)
+	Text     string `query:"text"`      // Custom text to display
+	Font     string `query:"font"`      // Typeface to use (e.g. "latin", "cjk"); empty auto-detects from Text
+	Weight   string `query:"weight"`    // Font weight ("regular", "bold")
+	Style    string `query:"style"`     // Font style ("normal", "italic")
+	Renderer string `query:"renderer"`  // Rendering backend ("gg", "draw2d"); empty defaults to gg
+	NoCache  string `query:"nocache"`   // Set to "1" to bypass both cache tiers
 }
 
 // HandlerImage processes placeholder image requests and returns generated images
@@ -43,6 +48,9 @@ func HandlerImage(c *fiber.Ctx) error {
 
 	if len(parts) == 2 {
 		imageType = parts[1]
+	} else if accept := c.Get("Accept"); accept != "" {
+		// No explicit extension: pick the best format the client advertises
+		imageType = negotiateType(accept)
 	}
 
 	if params.Text != "" {
@@ -56,7 +64,18 @@ func HandlerImage(c *fiber.Ctx) error {
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	imageData, err := generator.GenerateImage(req)
+	c.Set("Vary", "Accept")
+
+	noCache := params.NoCache == "1"
+	etag := `"` + generator.getCacheKey(req) + `"`
+	if !noCache {
+		c.Set("ETag", etag)
+		if c.Get("If-None-Match") == etag {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+	}
+
+	imageData, err := generator.GenerateImage(req, noCache)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -68,6 +87,24 @@ func HandlerImage(c *fiber.Ctx) error {
 	return c.Send(imageData)
 }
 
+// negotiateType picks an image format from an Accept header, mirroring how
+// modern image CDNs auto-serve WebP to capable browsers. Returns "" when
+// nothing matches, leaving NewImageRequest's own default in place. Formats
+// the client accepts but this service can't encode (e.g. avif) are skipped.
+func negotiateType(accept string) string {
+	for _, t := range []string{"webp", "avif", "png", "jpeg", "gif"} {
+		if strings.Contains(accept, "image/"+t) {
+			if ValidateType(t) {
+				return t
+			}
+		}
+	}
+	if strings.Contains(accept, "image/*") {
+		return "webp"
+	}
+	return ""
+}
+
 // getContentType returns appropriate MIME type for image format
 func getContentType(imageType string) string {
 	switch imageType {
@@ -79,6 +116,8 @@ func getContentType(imageType string) string {
 		return "image/gif"
 	case "webp":
 		return "image/webp"
+	case "svg":
+		return "image/svg+xml"
 	default:
 		return "image/png"
 	}
@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestNegotiateType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"image/webp,image/*,*/*", "webp"},
+		{"image/avif,image/png", "png"},
+		{"image/png", "png"},
+		{"image/gif", "gif"},
+		{"image/*", "webp"},
+		{"text/html", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateType(tt.accept); got != tt.want {
+			t.Errorf("negotiateType(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// textLine is one line of text together with its measured advance width.
+type textLine struct {
+	Text  string
+	Width fixed.Int26_6
+}
+
+// textBlock is a laid-out, potentially multi-line run of text measured by
+// real glyph ink bounds rather than line-box metrics, so it can be centered
+// precisely regardless of descenders or tall CJK glyphs.
+type textBlock struct {
+	Lines      []textLine
+	LineHeight fixed.Int26_6 // baseline-to-baseline advance
+	InkTop     fixed.Int26_6 // top of the inked block, relative to the first line's baseline
+	InkBottom  fixed.Int26_6 // bottom of the inked block, relative to the first line's baseline
+}
+
+// fits reports whether every line's width and the block's total inked
+// height stay within the given bounds. A non-positive bound is ignored.
+func (b *textBlock) fits(maxWidth, maxHeight fixed.Int26_6) bool {
+	if maxWidth > 0 {
+		for _, l := range b.Lines {
+			if l.Width > maxWidth {
+				return false
+			}
+		}
+	}
+	if maxHeight > 0 && b.InkBottom-b.InkTop > maxHeight {
+		return false
+	}
+	return true
+}
+
+// ppem converts a point size and DPI into the pixels-per-em unit the sfnt
+// glyph APIs expect.
+func ppem(size, dpi float64) fixed.Int26_6 {
+	return fixed.Int26_6((size * dpi / 72) * 64)
+}
+
+// layoutText splits text into lines on explicit "\n" breaks (auto-wrapping
+// any line whose advance would exceed maxWidth) and measures the resulting
+// block's real glyph-ink bounds via the font's metrics.
+func layoutText(parsed *opentype.Font, face font.Face, text string, size, dpi float64, maxWidth fixed.Int26_6) (*textBlock, error) {
+	var buf sfnt.Buffer
+	px := ppem(size, dpi)
+	metrics, err := parsed.Metrics(&buf, px, font.HintingNone)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []textLine
+	for _, raw := range strings.Split(text, "\n") {
+		lines = append(lines, wrapLine(face, raw, maxWidth)...)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, textLine{})
+	}
+
+	var top, bottom fixed.Int26_6
+	for i, line := range lines {
+		baseline := fixed.Int26_6(i) * metrics.Height
+		lineTop, lineBottom, err := inkExtent(parsed, &buf, px, line.Text)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 || baseline+lineTop < top {
+			top = baseline + lineTop
+		}
+		if i == 0 || baseline+lineBottom > bottom {
+			bottom = baseline + lineBottom
+		}
+	}
+
+	return &textBlock{Lines: lines, LineHeight: metrics.Height, InkTop: top, InkBottom: bottom}, nil
+}
+
+// inkExtent returns a single line's inked glyph bounds (top and bottom),
+// relative to its own baseline.
+func inkExtent(parsed *opentype.Font, buf *sfnt.Buffer, px fixed.Int26_6, line string) (top, bottom fixed.Int26_6, err error) {
+	first := true
+	for _, r := range line {
+		gi, err := parsed.GlyphIndex(buf, r)
+		if err != nil {
+			return 0, 0, err
+		}
+		if gi == 0 {
+			continue // .notdef / missing glyph doesn't contribute ink
+		}
+
+		b, _, err := parsed.GlyphBounds(buf, gi, px, font.HintingNone)
+		if err != nil {
+			return 0, 0, err
+		}
+		if first {
+			top, bottom = b.Min.Y, b.Max.Y
+			first = false
+			continue
+		}
+		if b.Min.Y < top {
+			top = b.Min.Y
+		}
+		if b.Max.Y > bottom {
+			bottom = b.Max.Y
+		}
+	}
+	return top, bottom, nil
+}
+
+// wrapLine splits a single line into width-bounded sublines by breaking on
+// spaces using the face's advance widths. A single-word line (or a single
+// word) that still exceeds maxWidth is left intact rather than broken mid-word. Lines that
+// already fit (or can't be split) are returned verbatim, preserving any
+// whitespace the caller passed in rather than collapsing it.
+func wrapLine(face font.Face, line string, maxWidth fixed.Int26_6) []textLine {
+	lineWidth := measureWidth(face, line)
+	words := strings.Fields(line)
+	if maxWidth <= 0 || len(words) <= 1 || lineWidth <= maxWidth {
+		return []textLine{{Text: line, Width: lineWidth}}
+	}
+
+	var out []textLine
+	current := words[0]
+	for _, word := range words[1:] {
+		candidate := current + " " + word
+		if measureWidth(face, candidate) > maxWidth {
+			out = append(out, textLine{Text: current, Width: measureWidth(face, current)})
+			current = word
+			continue
+		}
+		current = candidate
+	}
+	return append(out, textLine{Text: current, Width: measureWidth(face, current)})
+}
+
+// measureWidth returns the total advance width of s using face's glyph advances.
+func measureWidth(face font.Face, s string) fixed.Int26_6 {
+	var width fixed.Int26_6
+	for _, r := range s {
+		if adv, ok := face.GlyphAdvance(r); ok {
+			width += adv
+		}
+	}
+	return width
+}
+
+// fixedToFloat converts a fixed.Int26_6 to floating-point pixels.
+func fixedToFloat(f fixed.Int26_6) float64 {
+	return float64(f) / 64
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// fixedWidthFace is a minimal font.Face stub that gives every rune the same
+// advance, so wrapLine's wrapping decisions are deterministic without
+// depending on a real font's glyph metrics.
+type fixedWidthFace struct {
+	advance fixed.Int26_6
+}
+
+func (f fixedWidthFace) Close() error { return nil }
+func (f fixedWidthFace) Glyph(fixed.Point26_6, rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return image.Rectangle{}, nil, image.Point{}, f.advance, true
+}
+func (f fixedWidthFace) GlyphBounds(rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return fixed.Rectangle26_6{}, f.advance, true
+}
+func (f fixedWidthFace) GlyphAdvance(rune) (fixed.Int26_6, bool) { return f.advance, true }
+func (f fixedWidthFace) Kern(rune, rune) fixed.Int26_6           { return 0 }
+func (f fixedWidthFace) Metrics() font.Metrics                   { return font.Metrics{} }
+
+func TestWrapLinePreservesWhitespaceWhenNoWrapNeeded(t *testing.T) {
+	face := fixedWidthFace{advance: fixed.I(1)}
+
+	lines := wrapLine(face, "Hello  World", fixed.I(1000))
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1", len(lines))
+	}
+	if lines[0].Text != "Hello  World" {
+		t.Errorf("wrapLine collapsed whitespace: got %q, want %q", lines[0].Text, "Hello  World")
+	}
+}
+
+func TestWrapLineSplitsOnOverflow(t *testing.T) {
+	face := fixedWidthFace{advance: fixed.I(10)}
+
+	// Words are 3-5 glyphs wide (30-50 units); any two words plus the joining
+	// space exceed 65 units, so every word ends up on its own line.
+	lines := wrapLine(face, "one two three four", fixed.I(65))
+	if len(lines) < 2 {
+		t.Fatalf("got %d lines, want at least 2", len(lines))
+	}
+	for _, l := range lines {
+		if l.Width > fixed.I(65) {
+			t.Errorf("line %q exceeds maxWidth: %v > %v", l.Text, l.Width, fixed.I(65))
+		}
+	}
+}
+
+func TestWrapLineSingleWordNeverSplits(t *testing.T) {
+	face := fixedWidthFace{advance: fixed.I(100)}
+
+	lines := wrapLine(face, "supercalifragilisticexpialidocious", fixed.I(10))
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want 1 (single word must not be split mid-word)", len(lines))
+	}
+}
+
+func TestWidestLine(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"World", "World"},
+		{"Hi\nWorld", "World"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := widestLine(tt.text); got != tt.want {
+			t.Errorf("widestLine(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutTextMultiLine(t *testing.T) {
+	reg := NewFontRegistry()
+	parsed, key, err := reg.Font(TypefaceLatin, StyleNormal, WeightBold)
+	if err != nil {
+		t.Fatalf("Font: %v", err)
+	}
+	face, err := reg.Face(key.Typeface, key.Style, key.Weight, 24, DefaultDPI)
+	if err != nil {
+		t.Fatalf("Face: %v", err)
+	}
+
+	block, err := layoutText(parsed, face, "Hi\nWorld", 24, DefaultDPI, 0)
+	if err != nil {
+		t.Fatalf("layoutText: %v", err)
+	}
+	if len(block.Lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(block.Lines))
+	}
+	if block.Lines[0].Text != "Hi" || block.Lines[1].Text != "World" {
+		t.Errorf("got lines %q/%q, want \"Hi\"/\"World\"", block.Lines[0].Text, block.Lines[1].Text)
+	}
+	if block.InkBottom <= block.InkTop {
+		t.Errorf("InkBottom (%v) should exceed InkTop (%v) for non-empty text", block.InkBottom, block.InkTop)
+	}
+}
@@ -0,0 +1,152 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"log"
+	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"github.com/llgcode/draw2d"
+	"github.com/llgcode/draw2d/draw2dimg"
+	"github.com/llgcode/draw2d/draw2dkit"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// ttFontCache memoizes, per fontID, whether raw font bytes parse as a
+// truetype.Font draw2d can use natively, so a fresh Draw2DRenderer on every
+// request doesn't reparse (or re-attempt and fail to parse) the same bytes
+// each time. A nil value records a font that doesn't parse (e.g. the
+// CFF/OTTO-flavored .otf fonts this repo embeds, which
+// github.com/golang/freetype/truetype can't read), so that failure is only
+// logged once rather than on every request.
+var ttFontCache sync.Map // fontID string -> *truetype.Font
+
+// Draw2DRenderer implements Renderer on top of llgcode/draw2d, giving a
+// distinct antialiasing and path-fill pipeline from the default gg backend —
+// a stepping stone toward gradient and radial fills gg doesn't offer. Fill
+// and DrawTextAnchored both draw through draw2d's own GraphicContext
+// rasterizer, using a truetype.Font registered into draw2d's FontCache
+// rather than the font.Face the gg backend uses, falling back to
+// font.Face-based drawing when raw font bytes aren't available or don't parse
+// as a truetype.Font.
+type Draw2DRenderer struct {
+	img    *image.RGBA
+	gc     *draw2dimg.GraphicContext
+	face   font.Face
+	ttFont *truetype.Font
+	ttData draw2d.FontData
+	size   float64
+	dpi    float64
+	fg     color.Color
+}
+
+// NewDraw2DRenderer creates a draw2d-backed renderer for a canvas of the given size.
+func NewDraw2DRenderer(width, height int) *Draw2DRenderer {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	return &Draw2DRenderer{img: img, gc: draw2dimg.NewGraphicContext(img)}
+}
+
+func (r *Draw2DRenderer) Fill(c color.Color) {
+	b := r.img.Bounds()
+	r.gc.SetFillColor(c)
+	r.gc.BeginPath()
+	draw2dkit.Rectangle(r.gc, float64(b.Min.X), float64(b.Min.Y), float64(b.Max.X), float64(b.Max.Y))
+	r.gc.Fill()
+}
+
+// SetFont selects face for the font.Drawer fallback path and, when data is
+// non-nil, resolves it to a truetype.Font registered into draw2d's global
+// FontCache under fontID, so draw2d's own text pipeline can use it. The
+// parse is memoized in ttFontCache by fontID (a stable identifier for data,
+// not data itself) so repeated calls for the same font — one per request,
+// since a fresh Draw2DRenderer is built per request — don't reparse, and a
+// font that doesn't parse doesn't log or retry on every request. size and
+// dpi are the parameters face was minted with; draw2d's GraphicContext needs
+// them set explicitly via SetFontSize/SetDPI before it can measure or fill text.
+func (r *Draw2DRenderer) SetFont(face font.Face, data []byte, fontID string, size, dpi float64) {
+	r.face = face
+	r.size = size
+	r.dpi = dpi
+	r.ttFont = nil
+
+	if data == nil || fontID == "" {
+		return
+	}
+
+	if cached, ok := ttFontCache.Load(fontID); ok {
+		if ttFont, ok := cached.(*truetype.Font); ok && ttFont != nil {
+			r.ttFont = ttFont
+			r.ttData = draw2d.FontData{Name: fontID}
+		}
+		return
+	}
+
+	ttFont, err := truetype.Parse(data)
+	if err != nil {
+		log.Printf("draw2d renderer: font %q isn't usable via draw2d's native truetype text pipeline, falling back to font.Face rendering: %v", fontID, err)
+		ttFontCache.Store(fontID, (*truetype.Font)(nil))
+		return
+	}
+
+	r.ttFont = ttFont
+	r.ttData = draw2d.FontData{Name: fontID}
+	draw2d.RegisterFont(r.ttData, ttFont)
+	ttFontCache.Store(fontID, ttFont)
+}
+
+func (r *Draw2DRenderer) SetColor(c color.Color) {
+	r.fg = c
+}
+
+// DrawTextAnchored draws s with (x,y) positioned by anchor fractions (ax,ay)
+// relative to s's measured bounds, mirroring gg's DrawStringAnchored
+// semantics. When SetFont was given raw font bytes that parsed successfully,
+// this fills through draw2d's own GraphicContext.FillStringAt using the
+// registered truetype font; otherwise it falls back to drawing via
+// golang.org/x/image/font.Drawer directly onto the backing RGBA image using
+// the font.Face passed to SetFont.
+func (r *Draw2DRenderer) DrawTextAnchored(s string, x, y, ax, ay float64) {
+	if r.ttFont != nil {
+		r.drawTextDraw2D(s, x, y, ax, ay)
+		return
+	}
+	r.drawTextFace(s, x, y, ax, ay)
+}
+
+func (r *Draw2DRenderer) drawTextDraw2D(s string, x, y, ax, ay float64) {
+	r.gc.SetFontData(r.ttData)
+	r.gc.SetFontSize(r.size)
+	r.gc.SetDPI(int(r.dpi))
+	r.gc.SetFillColor(r.fg)
+
+	left, top, right, bottom := r.gc.GetStringBounds(s)
+	width, height := right-left, bottom-top
+	r.gc.FillStringAt(s, x-left-ax*width, y+ay*height)
+}
+
+func (r *Draw2DRenderer) drawTextFace(s string, x, y, ax, ay float64) {
+	if r.face == nil {
+		return
+	}
+
+	drawer := &font.Drawer{Dst: r.img, Src: image.NewUniform(r.fg), Face: r.face}
+	width := drawer.MeasureString(s)
+	metrics := r.face.Metrics()
+	height := fixedToFloat(metrics.Ascent + metrics.Descent)
+	drawer.Dot = fixed.Point26_6{
+		X: fixed.I(int(x)) - fixed.Int26_6(ax*float64(width)),
+		Y: fixed.I(int(y + ay*height)),
+	}
+	drawer.DrawString(s)
+}
+
+// fixedToFloat converts a fixed.Int26_6 to floating-point pixels.
+func fixedToFloat(f fixed.Int26_6) float64 {
+	return float64(f) / 64
+}
+
+func (r *Draw2DRenderer) Image() image.Image {
+	return r.img
+}
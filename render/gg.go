@@ -0,0 +1,41 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/fogleman/gg"
+	"golang.org/x/image/font"
+)
+
+// GGRenderer implements Renderer on top of fogleman/gg, the backend the
+// request pipeline has always used.
+type GGRenderer struct {
+	dc *gg.Context
+}
+
+// NewGGRenderer creates a gg-backed renderer for a canvas of the given size.
+func NewGGRenderer(width, height int) *GGRenderer {
+	return &GGRenderer{dc: gg.NewContext(width, height)}
+}
+
+func (r *GGRenderer) Fill(c color.Color) {
+	r.dc.SetColor(c)
+	r.dc.Clear()
+}
+
+func (r *GGRenderer) SetFont(face font.Face, _ []byte, _ string, _, _ float64) {
+	r.dc.SetFontFace(face)
+}
+
+func (r *GGRenderer) SetColor(c color.Color) {
+	r.dc.SetColor(c)
+}
+
+func (r *GGRenderer) DrawTextAnchored(s string, x, y, ax, ay float64) {
+	r.dc.DrawStringAnchored(s, x, y, ax, ay)
+}
+
+func (r *GGRenderer) Image() image.Image {
+	return r.dc.Image()
+}
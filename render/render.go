@@ -0,0 +1,51 @@
+// Package render abstracts the 2D drawing backend used to produce a
+// placeholder image, so the request pipeline doesn't depend on any one
+// rasterizer and new backends (different antialiasing, gradients, GPU
+// acceleration) can be added without touching image generation.
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+)
+
+// Backend names accepted by the ?renderer= query parameter.
+const (
+	BackendGG     = "gg"
+	BackendDraw2D = "draw2d"
+)
+
+// Renderer draws a background fill and anchored text onto a fixed-size
+// canvas and hands back the finished image.
+type Renderer interface {
+	// Fill paints the entire canvas with c.
+	Fill(c color.Color)
+	// SetFont selects the face subsequent DrawTextAnchored calls use. data
+	// holds the raw bytes face was parsed from, fontID a stable identifier
+	// for those bytes (stable across calls so a backend can cache work keyed
+	// on it instead of reparsing every request), and size/dpi the parameters
+	// face was minted with. These are for backends (e.g. Draw2DRenderer)
+	// that parse fonts through their own stack instead of drawing via
+	// font.Face directly; data may be nil if unavailable, in which case such
+	// backends fall back to font.Face-based drawing.
+	SetFont(face font.Face, data []byte, fontID string, size, dpi float64)
+	// SetColor selects the color subsequent DrawTextAnchored calls use.
+	SetColor(c color.Color)
+	// DrawTextAnchored draws s with (x,y) positioned by anchor fractions
+	// (ax,ay) relative to the string's bounding box, mirroring gg's
+	// DrawStringAnchored semantics.
+	DrawTextAnchored(s string, x, y, ax, ay float64)
+	// Image returns the finished canvas.
+	Image() image.Image
+}
+
+// New constructs the Renderer for the given backend name, defaulting to the
+// gg backend for an empty or unrecognized name.
+func New(backend string, width, height int) Renderer {
+	if backend == BackendDraw2D {
+		return NewDraw2DRenderer(width, height)
+	}
+	return NewGGRenderer(width, height)
+}
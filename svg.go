@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// svgLineHeightRatio approximates the raster path's baseline-to-baseline
+// advance as a multiple of font-size, since the SVG path has no parsed font
+// to ask for real metrics.
+const svgLineHeightRatio = 1.2
+
+// createSVG renders req directly to a compact SVG document, bypassing the
+// render.Renderer raster pipeline entirely: a background <rect> plus a centered
+// <text> element using the resolved bg/fg hex colors. Text is split on "\n"
+// (mirroring layoutText's line breaking) into one <tspan> per line, each
+// offset by dy so the whole block is vertically centered; width-based
+// auto-wrap isn't applied here since there's no parsed font to measure
+// against, but explicit line breaks render correctly instead of collapsing.
+func (ig *ImageGenerator) createSVG(req *ImageRequest) ([]byte, error) {
+	if !utf8.ValidString(req.Text) {
+		req.Text = "Invalid UTF-8"
+	}
+
+	fontSize := calculateOptimalFontSize(req.Width, req.Height, widestLine(req.Text))
+	lines := strings.Split(req.Text, "\n")
+	lineHeight := fontSize * svgLineHeightRatio
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`,
+		req.Width, req.Height, req.Width, req.Height)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#%s"/>`, req.BgColor)
+	fmt.Fprintf(&buf, `<text x="50%%" y="50%%" fill="#%s" font-size="%.2f" font-family="sans-serif" text-anchor="middle" dominant-baseline="central">`,
+		req.FgColor, fontSize)
+	for i, line := range lines {
+		dy := lineHeight
+		if i == 0 {
+			dy = -lineHeight * float64(len(lines)-1) / 2
+		}
+		fmt.Fprintf(&buf, `<tspan x="50%%" dy="%.2f">%s</tspan>`, dy, html.EscapeString(line))
+	}
+	buf.WriteString(`</text>`)
+	buf.WriteString(`</svg>`)
+
+	return buf.Bytes(), nil
+}
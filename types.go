@@ -10,12 +10,16 @@ import (
 
 // ImageRequest represents a request for generating a placeholder image
 type ImageRequest struct {
-	Width   int    // Image width in pixels
-	Height  int    // Image height in pixels
-	Type    string // Image format (png, jpg, gif, webp)
-	BgColor string // Background color in hex format
-	FgColor string // Foreground text color in hex format
-	Text    string // Text to display on the image
+	Width    int    // Image width in pixels
+	Height   int    // Image height in pixels
+	Type     string // Image format (png, jpg, jpeg, gif, webp, svg)
+	BgColor  string // Background color in hex format
+	FgColor  string // Foreground text color in hex format
+	Text     string // Text to display on the image
+	Font     string // Requested typeface, empty means auto-detect from Text
+	Weight   Weight // Requested font weight
+	Style    Style  // Requested font style
+	Renderer string // Rendering backend (render.BackendGG, render.BackendDraw2D); empty means gg
 }
 
 // ImageSize represents image dimensions
@@ -26,10 +30,35 @@ type ImageSize struct {
 
 var (
 	// Regular expressions for input validation
-	sizeRegex  = regexp.MustCompile(`^(\d+)x(\d+)$`)   // Matches WxH format
-	colorRegex = regexp.MustCompile(`^[a-fA-F0-9]{6}$`) // Matches 6-char hex color
+	sizeRegex       = regexp.MustCompile(`^(\d+)x(\d+)$`)                                               // Matches WxH format
+	colorRegex      = regexp.MustCompile(`^[a-fA-F0-9]{6}$`)                                             // Matches 6-char hex color
+	shortColorRegex = regexp.MustCompile(`^[a-fA-F0-9]{3}$`)                                              // Matches 3-char shorthand hex color
+	rgbColorRegex   = regexp.MustCompile(`^rgba?\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*(?:,\s*[\d.]+\s*)?\)$`) // Matches rgb()/rgba()
 )
 
+// namedColors maps CSS named colors (including the ones the startup log
+// advertises, e.g. bg=navy&fg=white) to their 6-character hex equivalent.
+var namedColors = map[string]string{
+	"black":   "000000",
+	"white":   "ffffff",
+	"red":     "ff0000",
+	"green":   "008000",
+	"blue":    "0000ff",
+	"navy":    "000080",
+	"gold":    "ffd700",
+	"gray":    "808080",
+	"grey":    "808080",
+	"orange":  "ffa500",
+	"purple":  "800080",
+	"pink":    "ffc0cb",
+	"yellow":  "ffff00",
+	"cyan":    "00ffff",
+	"magenta": "ff00ff",
+	"brown":   "a52a2a",
+	"silver":  "c0c0c0",
+	"teal":    "008080",
+}
+
 const (
 	MaxImageSize = 3000      // Maximum allowed image dimension
 	DefaultBg    = "cccccc"  // Default background color (light gray)
@@ -44,6 +73,7 @@ var validTypes = map[string]bool{
 	"jpg":  true, // Joint Photographic Experts Group
 	"jpeg": true, // Joint Photographic Experts Group (alt)
 	"webp": true, // WebP format
+	"svg":  true, // Scalable Vector Graphics
 }
 
 // ParseSize parses size string in WxH format (e.g., "300x200") into ImageSize struct
@@ -75,12 +105,41 @@ func ValidateType(imageType string) bool {
 	return validTypes[strings.ToLower(imageType)]
 }
 
-// ValidateColor validates hex color format (6 characters)
-func ValidateColor(color string) bool {
-	if color == "" {
-		return true
+// resolveColor normalizes a color input into a bare 6-character hex string.
+// Accepts 6-char hex, 3-char shorthand hex (optionally "#"-prefixed),
+// rgb()/rgba(), and CSS named colors (see namedColors).
+func resolveColor(input string) (string, error) {
+	s := strings.TrimPrefix(strings.TrimSpace(input), "#")
+
+	switch {
+	case colorRegex.MatchString(s):
+		return strings.ToLower(s), nil
+	case shortColorRegex.MatchString(s):
+		return strings.ToLower(string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})), nil
+	}
+
+	if m := rgbColorRegex.FindStringSubmatch(strings.ToLower(s)); m != nil {
+		return rgbToHex(m[1], m[2], m[3])
+	}
+
+	if hex, ok := namedColors[strings.ToLower(s)]; ok {
+		return hex, nil
+	}
+
+	return "", fmt.Errorf("invalid color: %s", input)
+}
+
+// rgbToHex converts decimal r,g,b channel strings (0-255) into 6-char hex.
+func rgbToHex(rs, gs, bs string) (string, error) {
+	channels := make([]int, 3)
+	for i, s := range []string{rs, gs, bs} {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 0 || v > 255 {
+			return "", fmt.Errorf("invalid rgb channel: %s", s)
+		}
+		channels[i] = v
 	}
-	return colorRegex.MatchString(color)
+	return fmt.Sprintf("%02x%02x%02x", channels[0], channels[1], channels[2]), nil
 }
 
 // applyDefault returns value if non-empty, otherwise returns defaultValue
@@ -108,25 +167,29 @@ func NewImageRequest(sizeStr, imageType string, p RequestQueryParams) (*ImageReq
 	}
 
 	// Process colors with defaults and validation
-	bg := applyDefault(p.Bg, DefaultBg)
-	if !ValidateColor(bg) {
-		return nil, fmt.Errorf("invalid background color: %s", bg)
+	bg, err := resolveColor(applyDefault(p.Bg, DefaultBg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid background color: %w", err)
 	}
 
-	fg := applyDefault(p.Fg, DefaultFg)  
-	if !ValidateColor(fg) {
-		return nil, fmt.Errorf("invalid foreground color: %s", fg)
+	fg, err := resolveColor(applyDefault(p.Fg, DefaultFg))
+	if err != nil {
+		return nil, fmt.Errorf("invalid foreground color: %w", err)
 	}
 
 	// Set text with fallback to dimensions
 	text := applyDefault(p.Text, fmt.Sprintf("%dx%d", size.Width, size.Height))
 
 	return &ImageRequest{
-		Width:   size.Width,
-		Height:  size.Height,
-		Type:    strings.ToLower(imageType),
-		BgColor: bg,
-		FgColor: fg,
-		Text:    text,
+		Width:    size.Width,
+		Height:   size.Height,
+		Type:     strings.ToLower(imageType),
+		BgColor:  bg,
+		FgColor:  fg,
+		Text:     text,
+		Font:     strings.ToLower(p.Font),
+		Weight:   ParseWeight(strings.ToLower(p.Weight)),
+		Style:    ParseStyle(strings.ToLower(p.Style)),
+		Renderer: strings.ToLower(p.Renderer),
 	}, nil
 }
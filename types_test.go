@@ -0,0 +1,57 @@
+package main
+
+import "testing"
+
+func TestResolveColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"ff0000", "ff0000"},
+		{"FF0000", "ff0000"},
+		{"#ff0000", "ff0000"},
+		{"f00", "ff0000"},
+		{"#f00", "ff0000"},
+		{"rgb(255, 0, 0)", "ff0000"},
+		{"rgba(255, 0, 0, 0.5)", "ff0000"},
+		{"red", "ff0000"},
+		{"navy", "000080"},
+		{"white", "ffffff"},
+		{"NAVY", "000080"},
+	}
+	for _, tt := range tests {
+		got, err := resolveColor(tt.in)
+		if err != nil {
+			t.Errorf("resolveColor(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveColor(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestResolveColorInvalid(t *testing.T) {
+	for _, in := range []string{"", "gggggg", "ff00", "notacolor", "rgb(256,0,0)"} {
+		if _, err := resolveColor(in); err == nil {
+			t.Errorf("resolveColor(%q) = nil error, want error", in)
+		}
+	}
+}
+
+func TestRgbToHex(t *testing.T) {
+	got, err := rgbToHex("255", "0", "128")
+	if err != nil {
+		t.Fatalf("rgbToHex: %v", err)
+	}
+	if got != "ff0080" {
+		t.Errorf("rgbToHex(255,0,128) = %q, want %q", got, "ff0080")
+	}
+
+	if _, err := rgbToHex("256", "0", "0"); err == nil {
+		t.Errorf("rgbToHex(256,0,0) = nil error, want error for out-of-range channel")
+	}
+	if _, err := rgbToHex("x", "0", "0"); err == nil {
+		t.Errorf("rgbToHex(x,0,0) = nil error, want error for non-numeric channel")
+	}
+}